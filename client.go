@@ -23,14 +23,33 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"path"
 	"strings"
-	"unicode/utf8"
 )
 
 type ClientConfig struct {
 	BaseURL *url.URL
+
+	// Transport is the base http.RoundTripper used to make requests.
+	// Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Middlewares wrap Transport, outermost first, letting callers inject
+	// cross-cutting behavior like auth headers or logging without
+	// wrapping every call. See the kbhttp/middleware subpackage for some
+	// ready-made ones.
+	Middlewares []func(http.RoundTripper) http.RoundTripper
+
+	// Jar holds cookies between calls on the same Client, so that
+	// session and CSRF cookies set by one request are sent on the next.
+	// Defaults to an empty in-memory jar.
+	Jar http.CookieJar
+
+	// Retry configures automatic retries for idempotent requests. The
+	// zero value disables retries.
+	Retry RetryConfig
 }
 
 type Client struct {
@@ -39,12 +58,38 @@ type Client struct {
 }
 
 func NewClient(config ClientConfig) *Client {
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if config.Retry.MaxAttempts > 1 {
+		transport = withRetry(config.Retry)(transport)
+	}
+	for i := len(config.Middlewares) - 1; i >= 0; i-- {
+		transport = config.Middlewares[i](transport)
+	}
+
+	jar := config.Jar
+	if jar == nil {
+		jar, _ = cookiejar.New(nil)
+	}
+
 	return &Client{
-		Client: http.DefaultClient,
+		Client: &http.Client{Transport: transport, Jar: jar},
 		config: config,
 	}
 }
 
+// Use registers additional middleware on top of the client's current
+// transport, in the order given. Unlike the Middlewares set in
+// ClientConfig, these wrap whatever transport is already in place, so
+// calling Use repeatedly keeps layering on top.
+func (c *Client) Use(mw ...func(http.RoundTripper) http.RoundTripper) {
+	for _, m := range mw {
+		c.Client.Transport = m(c.Client.Transport)
+	}
+}
+
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	if c.config.BaseURL == nil {
 		return c.Client.Do(req)
@@ -77,11 +122,7 @@ func (c *Client) DoJSON(req *http.Request, target any) error {
 		if err != nil {
 			return fmt.Errorf("got %d code and failed to read response body: %w", resp.StatusCode, err)
 		}
-
-		if !utf8.Valid(body) {
-			return fmt.Errorf("got %d code and %d bytes of binary data", resp.StatusCode, len(body))
-		}
-		return fmt.Errorf("got %d code and response: %s", resp.StatusCode, string(body))
+		return newResponseError(resp, body)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&target); err != nil {
@@ -148,11 +189,7 @@ func (c *Client) DoPage(req *http.Request) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("got %d code and failed to read response body: %w", resp.StatusCode, err)
 		}
-
-		if !utf8.Valid(body) {
-			return "", fmt.Errorf("got %d code and %d bytes of binary data", resp.StatusCode, len(body))
-		}
-		return "", fmt.Errorf("got %d code and response: %s", resp.StatusCode, string(body))
+		return "", newResponseError(resp, body)
 	}
 
 	body, err := io.ReadAll(resp.Body)
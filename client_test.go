@@ -48,6 +48,30 @@ func TestClientDo(t *testing.T) {
 	assert.Equal(t, `{"name": "joebob"}`, string(body))
 }
 
+func TestClientUseMiddleware(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/users/1", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Body: []byte(`{"name": "joebob"}`),
+	})
+
+	var gotAuth string
+	client.Use(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			gotAuth = req.Header.Get("Authorization")
+			return next.RoundTrip(req)
+		})
+	})
+
+	req, err := http.NewRequest("GET", "/users/1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sekrit")
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer sekrit", gotAuth)
+}
+
 // JSON
 //
 
@@ -0,0 +1,67 @@
+package kbhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ResponseError is returned by DoJSON and DoPage when the server responds
+// with a non-2xx status code. It carries the raw response so callers can
+// branch on status code or inspect headers/body instead of matching on the
+// formatted error string.
+type ResponseError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+
+	// JSON holds the decoded response body when the response's
+	// Content-Type is application/json and the body decodes successfully.
+	// It is nil otherwise.
+	JSON any
+}
+
+func newResponseError(resp *http.Response, body []byte) *ResponseError {
+	respErr := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+	}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		var parsed any
+		if err := json.Unmarshal(body, &parsed); err == nil {
+			respErr.JSON = parsed
+		}
+	}
+
+	return respErr
+}
+
+func (e *ResponseError) Error() string {
+	if !utf8.Valid(e.Body) {
+		return fmt.Sprintf("got %d code and %d bytes of binary data", e.StatusCode, len(e.Body))
+	}
+	return fmt.Sprintf("got %d code and response: %s", e.StatusCode, string(e.Body))
+}
+
+// IsStatus reports whether err is a *ResponseError with the given status code.
+func IsStatus(err error, code int) bool {
+	respErr, ok := AsResponseError(err)
+	return ok && respErr.StatusCode == code
+}
+
+// AsResponseError unwraps err looking for a *ResponseError, in the same
+// manner as errors.As.
+func AsResponseError(err error) (*ResponseError, bool) {
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr, true
+	}
+	return nil, false
+}
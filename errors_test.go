@@ -0,0 +1,52 @@
+package kbhttp
+
+import (
+	"testing"
+
+	"github.com/dankinder/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDoJSONResponseError(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/users/1", jsonHeaderMatcher, mock.Anything).Return(httpmock.Response{
+		Status: 404,
+		Header: map[string][]string{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"error": "not found"}`),
+	})
+
+	var user TestUser
+	err := client.GetJSON("/users/1", &user)
+	require.Error(t, err)
+
+	require.True(t, IsStatus(err, 404))
+	assert.False(t, IsStatus(err, 500))
+
+	respErr, ok := AsResponseError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, respErr.StatusCode)
+	assert.Equal(t, map[string]any{"error": "not found"}, respErr.JSON)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestClientDoPageResponseError(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/users/1", htmlHeaderMatcher, mock.Anything).Return(httpmock.Response{
+		Status: 500,
+		Body:   []byte(`<html>boom</html>`),
+	})
+
+	_, err := client.GetPage("/users/1")
+	require.Error(t, err)
+
+	respErr, ok := AsResponseError(err)
+	require.True(t, ok)
+	assert.Equal(t, 500, respErr.StatusCode)
+	assert.Nil(t, respErr.JSON)
+}
@@ -0,0 +1,119 @@
+package kbhttp
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Login posts values to path (typically a login form's action) and
+// discards the resulting page. Since Client carries a cookie jar, the
+// session cookie set by the server is sent on subsequent requests.
+func (c *Client) Login(path string, values url.Values) error {
+	_, err := c.PostPage(path, values)
+	return err
+}
+
+// CSRFToken fetches page and returns the value of the hidden input named
+// fieldName inside the first element matching formSelector, a simple CSS
+// selector of the form "tag", "#id" or "tag#id". It's meant for pulling
+// the CSRF token Katabole apps render into forms, so that tests can
+// submit those forms without a browser.
+func (c *Client) CSRFToken(page, formSelector, fieldName string) (string, error) {
+	body, err := c.GetPage(page)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %q as HTML: %w", page, err)
+	}
+
+	form := findElement(doc, formSelector)
+	if form == nil {
+		return "", fmt.Errorf("no element matching %q found on %q", formSelector, page)
+	}
+
+	input := findInput(form, fieldName)
+	if input == nil {
+		return "", fmt.Errorf("no input named %q found in %q on %q", fieldName, formSelector, page)
+	}
+
+	return attr(input, "value"), nil
+}
+
+// PostForm posts values to path after adding the CSRF token found in
+// formSelector's fieldName input on page, so callers don't have to wire
+// CSRFToken into every form submission by hand.
+func (c *Client) PostForm(page, formSelector, fieldName, path string, values url.Values) (string, error) {
+	token, err := c.CSRFToken(page, formSelector, fieldName)
+	if err != nil {
+		return "", err
+	}
+
+	withToken := url.Values{}
+	for k, v := range values {
+		withToken[k] = v
+	}
+	withToken.Set(fieldName, token)
+
+	return c.PostPage(path, withToken)
+}
+
+// findElement walks the tree rooted at n for the first element matching a
+// selector of the form "tag", "#id" or "tag#id".
+func findElement(n *html.Node, selector string) *html.Node {
+	tag, id := "", ""
+	if before, after, ok := strings.Cut(selector, "#"); ok {
+		tag, id = before, after
+	} else {
+		tag = selector
+	}
+
+	var walk func(*html.Node) *html.Node
+	walk = func(n *html.Node) *html.Node {
+		if n.Type == html.ElementNode {
+			if (tag == "" || n.Data == tag) && (id == "" || attr(n, "id") == id) {
+				return n
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if found := walk(c); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return walk(n)
+}
+
+// findInput walks the tree rooted at n for the first <input> element with
+// the given name attribute.
+func findInput(n *html.Node, name string) *html.Node {
+	var walk func(*html.Node) *html.Node
+	walk = func(n *html.Node) *html.Node {
+		if n.Type == html.ElementNode && n.Data == "input" && attr(n, "name") == name {
+			return n
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if found := walk(c); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return walk(n)
+}
+
+// attr returns the value of the named attribute on n, or "" if absent.
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
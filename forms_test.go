@@ -0,0 +1,74 @@
+package kbhttp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dankinder/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const loginPage = `<html><body>
+<form id="login-form" method="post" action="/login">
+<input type="hidden" name="csrf_token" value="tok-123">
+<input type="text" name="email">
+</form>
+</body></html>`
+
+func TestClientCSRFToken(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/login", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Body: []byte(loginPage),
+	})
+
+	token, err := client.CSRFToken("/login", "form#login-form", "csrf_token")
+	require.NoError(t, err)
+	assert.Equal(t, "tok-123", token)
+}
+
+func TestClientCSRFTokenNotFound(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/login", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Body: []byte(loginPage),
+	})
+
+	_, err := client.CSRFToken("/login", "form#signup-form", "csrf_token")
+	require.Error(t, err)
+}
+
+func TestClientLogin(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	vals := url.Values{"email": []string{"joe@example.com"}}
+	handler.On("HandleWithHeaders", "POST", "/login", mock.Anything, []byte(vals.Encode())).Return(httpmock.Response{
+		Body: []byte(`<html>welcome</html>`),
+	})
+
+	require.NoError(t, client.Login("/login", vals))
+}
+
+func TestClientPostForm(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/login", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Body: []byte(loginPage),
+	})
+
+	want := url.Values{"email": []string{"joe@example.com"}, "csrf_token": []string{"tok-123"}}
+	handler.On("HandleWithHeaders", "POST", "/login", mock.Anything, []byte(want.Encode())).Return(httpmock.Response{
+		Body: []byte(`<html>welcome</html>`),
+	})
+
+	page, err := client.PostForm("/login", "form#login-form", "csrf_token", "/login",
+		url.Values{"email": []string{"joe@example.com"}})
+	require.NoError(t, err)
+	assert.Contains(t, page, "welcome")
+}
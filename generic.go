@@ -0,0 +1,63 @@
+package kbhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// Get is a generic counterpart to Client.GetJSON that returns the decoded
+// value directly instead of decoding into a passed-in pointer.
+func Get[T any](c *Client, urlPath string) (T, error) {
+	var target T
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return target, err
+	}
+	err = c.DoJSON(req, &target)
+	return target, err
+}
+
+// Post is a generic counterpart to Client.PostJSON that returns the
+// decoded response directly instead of decoding into a passed-in pointer.
+func Post[Req, Resp any](c *Client, urlPath string, body Req) (Resp, error) {
+	var target Resp
+	data, err := json.Marshal(body)
+	if err != nil {
+		return target, err
+	}
+	req, err := http.NewRequest(http.MethodPost, urlPath, bytes.NewReader(data))
+	if err != nil {
+		return target, err
+	}
+	err = c.DoJSON(req, &target)
+	return target, err
+}
+
+// Put is a generic counterpart to Client.PutJSON that returns the decoded
+// response directly instead of decoding into a passed-in pointer.
+func Put[Req, Resp any](c *Client, urlPath string, body Req) (Resp, error) {
+	var target Resp
+	data, err := json.Marshal(body)
+	if err != nil {
+		return target, err
+	}
+	req, err := http.NewRequest(http.MethodPut, urlPath, bytes.NewReader(data))
+	if err != nil {
+		return target, err
+	}
+	err = c.DoJSON(req, &target)
+	return target, err
+}
+
+// Delete is a generic counterpart to Client.DeleteJSON that returns the
+// decoded response directly instead of decoding into a passed-in pointer.
+func Delete[Resp any](c *Client, urlPath string) (Resp, error) {
+	var target Resp
+	req, err := http.NewRequest(http.MethodDelete, urlPath, nil)
+	if err != nil {
+		return target, err
+	}
+	err = c.DoJSON(req, &target)
+	return target, err
+}
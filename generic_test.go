@@ -0,0 +1,65 @@
+package kbhttp
+
+import (
+	"testing"
+
+	"github.com/dankinder/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "GET", "/users/1", jsonHeaderMatcher, mock.Anything).Return(httpmock.Response{
+		Body: []byte(`{"id": 1, "name": "joebob"}`),
+	})
+
+	u, err := Get[TestUser](client, "/users/1")
+	require.NoError(t, err)
+	assert.Equal(t, "joebob", u.Name)
+	assert.Equal(t, 1, u.ID)
+}
+
+func TestPost(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	u := TestUser{Name: "joebob"}
+	handler.On("HandleWithHeaders", "POST", "/users", jsonHeaderMatcher, httpmock.JSONMatcher(&u)).Return(httpmock.Response{
+		Body: []byte(`{"id": 9000, "name": "joebob"}`),
+	})
+
+	created, err := Post[TestUser, TestUser](client, "/users", u)
+	require.NoError(t, err)
+	assert.Equal(t, 9000, created.ID)
+}
+
+func TestPut(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	u := TestUser{ID: 9000, Name: "joebob"}
+	handler.On("HandleWithHeaders", "PUT", "/users/9000", jsonHeaderMatcher, httpmock.JSONMatcher(&u)).Return(httpmock.Response{
+		Body: []byte(`{"id": 9000, "name": "joebob"}`),
+	})
+
+	updated, err := Put[TestUser, TestUser](client, "/users/9000", u)
+	require.NoError(t, err)
+	assert.Equal(t, "joebob", updated.Name)
+}
+
+func TestDelete(t *testing.T) {
+	handler, client, cleanup := setup(t)
+	defer cleanup()
+
+	handler.On("HandleWithHeaders", "DELETE", "/users/9000", jsonHeaderMatcher, mock.Anything).Return(httpmock.Response{
+		Body: []byte(`{"result": "ok"}`),
+	})
+
+	result, err := Delete[map[string]string](client, "/users/9000")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result["result"])
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+)
+
+// Logger dumps each request and its response to w, for debugging failing
+// tests against a live or mock server.
+func Logger(w io.Writer) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+				fmt.Fprintf(w, "%s\n", dump)
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				fmt.Fprintf(w, "error: %v\n", err)
+				return resp, err
+			}
+
+			if dump, dumpErr := httputil.DumpResponse(resp, true); dumpErr == nil {
+				fmt.Fprintf(w, "%s\n", dump)
+			}
+			return resp, err
+		})
+	}
+}
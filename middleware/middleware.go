@@ -0,0 +1,43 @@
+// Package middleware provides a few ready-made kbhttp.Client middlewares:
+// transports that wrap an http.RoundTripper to add cross-cutting behavior
+// like auth headers or logging without touching every call site.
+package middleware
+
+import (
+	"net/http"
+)
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// BearerAuth sets an "Authorization: Bearer <token>" header on every request.
+func BearerAuth(token string) func(http.RoundTripper) http.RoundTripper {
+	return Header("Authorization", "Bearer "+token)
+}
+
+// BasicAuth sets HTTP Basic Auth credentials on every request.
+func BasicAuth(user, pass string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.SetBasicAuth(user, pass)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Header sets a fixed header on every request, overwriting any existing
+// value with the same key.
+func Header(key, value string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
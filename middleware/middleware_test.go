@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBearerAuth(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: BearerAuth("sekrit")(http.DefaultTransport)}
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer sekrit", gotAuth)
+}
+
+func TestBasicAuth(t *testing.T) {
+	var gotUser, gotPass string
+	var gotOK bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotPass, gotOK = r.BasicAuth()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: BasicAuth("alice", "hunter2")(http.DefaultTransport)}
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	require.True(t, gotOK)
+	assert.Equal(t, "alice", gotUser)
+	assert.Equal(t, "hunter2", gotPass)
+}
+
+func TestHeader(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-ID")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Header("X-Request-ID", "abc123")(http.DefaultTransport)}
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", gotHeader)
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "25")
+	}))
+	defer server.Close()
+
+	rl := &RateLimit{}
+	client := &http.Client{Transport: rl.Middleware()(http.DefaultTransport)}
+
+	assert.Equal(t, 0.0, rl.FractionReached())
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, 0.75, rl.FractionReached())
+}
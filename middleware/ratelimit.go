@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RateLimit tracks the X-RateLimit-Limit and X-RateLimit-Remaining
+// headers on responses, letting callers check how close they are to
+// being throttled without parsing headers themselves.
+type RateLimit struct {
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	seen      bool
+}
+
+// Middleware returns the http.RoundTripper wrapper that updates r from
+// response headers.
+func (r *RateLimit) Middleware() func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return resp, err
+			}
+
+			limit, limitErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+			remaining, remainingErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+			if limitErr == nil && remainingErr == nil {
+				r.mu.Lock()
+				r.limit = limit
+				r.remaining = remaining
+				r.seen = true
+				r.mu.Unlock()
+			}
+
+			return resp, nil
+		})
+	}
+}
+
+// FractionReached returns how much of the rate limit has been used, as a
+// value between 0 and 1. It returns 0 if no response has carried
+// rate-limit headers yet.
+func (r *RateLimit) FractionReached() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.seen || r.limit == 0 {
+		return 0
+	}
+	return 1 - float64(r.remaining)/float64(r.limit)
+}
@@ -0,0 +1,68 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Method        string      `json:"method"`
+	Path          string      `json:"path"`
+	RequestHeader http.Header `json:"requestHeader,omitempty"`
+	RequestBody   []byte      `json:"requestBody,omitempty"`
+
+	Status         int         `json:"status"`
+	ResponseHeader http.Header `json:"responseHeader,omitempty"`
+	ResponseBody   []byte      `json:"responseBody,omitempty"`
+}
+
+func (i Interaction) response() *http.Response {
+	header := i.ResponseHeader
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: i.Status,
+		Status:     http.StatusText(i.Status),
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(i.ResponseBody)),
+	}
+}
+
+// Cassette is a named, ordered list of recorded interactions, persisted
+// as JSON on disk.
+type Cassette struct {
+	Path         string        `json:"-"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+func loadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	c.Path = path
+	return &c, nil
+}
+
+func (c *Cassette) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, data, 0o644)
+}
@@ -0,0 +1,175 @@
+// Package recorder lets kbhttp tests run against a recorded cassette of
+// HTTP interactions instead of a live server, so that fixtures checked
+// into testdata/ can stand in for a real Katabole app.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// Mode controls how a Recorder handles requests.
+type Mode int
+
+const (
+	// Live forwards every request to the underlying RoundTripper and
+	// records nothing.
+	Live Mode = iota
+	// Record forwards every request to the underlying RoundTripper and
+	// appends the request/response pair to the cassette.
+	Record
+	// Replay matches every request against the cassette and returns the
+	// stored response, making no network calls. An unmatched request is
+	// an error.
+	Replay
+)
+
+// Matcher reports whether req matches a recorded Interaction.
+type Matcher func(req *http.Request, body []byte, i Interaction) bool
+
+// MethodAndPath matches on request method and URL path only, ignoring
+// headers and body. It's the default Matcher.
+func MethodAndPath(req *http.Request, body []byte, i Interaction) bool {
+	return req.Method == i.Method && req.URL.Path == i.Path
+}
+
+// JSONBody wraps a Matcher to additionally require the recorded and
+// incoming request bodies to be byte-identical once leading and trailing
+// whitespace is trimmed, for endpoints where the method and path alone
+// aren't selective enough.
+func JSONBody(next Matcher) Matcher {
+	return func(req *http.Request, body []byte, i Interaction) bool {
+		return next(req, body, i) && bytes.Equal(bytes.TrimSpace(body), bytes.TrimSpace(i.RequestBody))
+	}
+}
+
+// HeaderSubset wraps a Matcher to additionally require that the recorded
+// request carries at least the given key/value pairs, for endpoints
+// distinguished by a header like Authorization or X-Tenant-ID.
+func HeaderSubset(next Matcher, header http.Header) Matcher {
+	return func(req *http.Request, body []byte, i Interaction) bool {
+		if !next(req, body, i) {
+			return false
+		}
+		for key, values := range header {
+			if !reflect.DeepEqual(i.RequestHeader.Values(key), values) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+// against a Cassette, depending on Mode.
+type Recorder struct {
+	Mode     Mode
+	Matcher  Matcher
+	Next     http.RoundTripper
+	cassette *Cassette
+	replayed map[int]bool
+}
+
+// New returns a Recorder that loads (in Replay mode) or creates (in
+// Record mode) the cassette at path. next is the RoundTripper used to
+// make real requests in Live and Record mode; it's ignored in Replay
+// mode.
+func New(mode Mode, path string, next http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{Mode: mode, Matcher: MethodAndPath, Next: next}
+
+	switch mode {
+	case Replay:
+		cassette, err := loadCassette(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassette %q: %w", path, err)
+		}
+		r.cassette = cassette
+	case Record:
+		r.cassette = &Cassette{Path: path}
+	}
+
+	return r, nil
+}
+
+// Save writes the recorded cassette to disk. It's a no-op outside Record
+// mode.
+func (r *Recorder) Save() error {
+	if r.Mode != Record {
+		return nil
+	}
+	return r.cassette.save()
+}
+
+// Remaining returns the number of cassette interactions that haven't
+// been replayed yet, so a test can assert the whole cassette was
+// consumed (e.g. `require.Zero(t, rec.Remaining())` in cleanup).
+func (r *Recorder) Remaining() int {
+	if r.cassette == nil {
+		return 0
+	}
+	return len(r.cassette.Interactions) - len(r.replayed)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.Mode == Live {
+		return r.Next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.Mode == Replay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	for i := range r.cassette.Interactions {
+		it := r.cassette.Interactions[i]
+		if r.Matcher(req, body, it) {
+			if r.replayed == nil {
+				r.replayed = map[int]bool{}
+			}
+			r.replayed[i] = true
+			return it.response(), nil
+		}
+	}
+	return nil, fmt.Errorf("no recorded interaction matches %s %s", req.Method, req.URL.Path)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:         req.Method,
+		Path:           req.URL.Path,
+		RequestHeader:  req.Header.Clone(),
+		RequestBody:    body,
+		Status:         resp.StatusCode,
+		ResponseHeader: resp.Header.Clone(),
+		ResponseBody:   respBody,
+	})
+
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"name": "joebob"}`))
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "users.json")
+
+	rec, err := New(Record, cassettePath, http.DefaultTransport)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("GET", server.URL+"/users/1", nil)
+	require.NoError(t, err)
+
+	resp, err := rec.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "joebob"}`, string(body))
+
+	require.NoError(t, rec.Save())
+
+	replay, err := New(Replay, cassettePath, nil)
+	require.NoError(t, err)
+
+	req2, err := http.NewRequest("GET", "http://example.com/users/1", nil)
+	require.NoError(t, err)
+
+	resp2, err := replay.RoundTrip(req2)
+	require.NoError(t, err)
+	body2, err := io.ReadAll(resp2.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "joebob"}`, string(body2))
+	assert.Equal(t, "application/json", resp2.Header.Get("Content-Type"))
+	assert.Zero(t, replay.Remaining())
+}
+
+func TestRecorderRemaining(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "users.json")
+	cassette := &Cassette{Path: cassettePath, Interactions: []Interaction{
+		{Method: "GET", Path: "/users/1", Status: 200, ResponseBody: []byte(`{}`)},
+		{Method: "GET", Path: "/users/2", Status: 200, ResponseBody: []byte(`{}`)},
+	}}
+	require.NoError(t, cassette.save())
+
+	replay, err := New(Replay, cassettePath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replay.Remaining())
+
+	req, err := http.NewRequest("GET", "http://example.com/users/1", nil)
+	require.NoError(t, err)
+	_, err = replay.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replay.Remaining())
+}
+
+func TestRecorderHeaderSubsetMatcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tenant: " + r.Header.Get("X-Tenant-ID")))
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "tenants.json")
+
+	rec, err := New(Record, cassettePath, http.DefaultTransport)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("GET", server.URL+"/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Tenant-ID", "acme")
+	_, err = rec.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+
+	replay, err := New(Replay, cassettePath, nil)
+	require.NoError(t, err)
+	replay.Matcher = HeaderSubset(MethodAndPath, http.Header{"X-Tenant-Id": {"acme"}})
+
+	match, err := http.NewRequest("GET", "http://example.com/ping", nil)
+	require.NoError(t, err)
+	match.Header.Set("X-Tenant-ID", "acme")
+	_, err = replay.RoundTrip(match)
+	require.NoError(t, err)
+
+	replay.Matcher = HeaderSubset(MethodAndPath, http.Header{"X-Tenant-Id": {"other"}})
+	mismatch, err := http.NewRequest("GET", "http://example.com/ping", nil)
+	require.NoError(t, err)
+	_, err = replay.RoundTrip(mismatch)
+	require.Error(t, err)
+}
+
+func TestRecorderReplayUnmatched(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, (&Cassette{Path: cassettePath}).save())
+
+	replay, err := New(Replay, cassettePath, nil)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("GET", "http://example.com/users/1", nil)
+	require.NoError(t, err)
+
+	_, err = replay.RoundTrip(req)
+	require.Error(t, err)
+}
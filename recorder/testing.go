@@ -0,0 +1,39 @@
+package recorder
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+// WithCassette returns a kbhttp middleware that replays the cassette at
+// path if it exists, or records a fresh one there otherwise (set the
+// KBHTTP_RECORD environment variable to force re-recording against a
+// live server). It registers a t.Cleanup to write the cassette to disk
+// when recording, so tests need no explicit teardown.
+//
+//	client := kbhttp.NewClient(kbhttp.ClientConfig{
+//		BaseURL:     mustParse(server.URL()),
+//		Middlewares: []func(http.RoundTripper) http.RoundTripper{recorder.WithCassette(t, "testdata/users.json")},
+//	})
+func WithCassette(t *testing.T, path string) func(http.RoundTripper) http.RoundTripper {
+	mode := Replay
+	if _, err := os.Stat(path); os.IsNotExist(err) || os.Getenv("KBHTTP_RECORD") != "" {
+		mode = Record
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		rec, err := New(mode, path, next)
+		if err != nil {
+			t.Fatalf("failed to set up cassette %q: %v", path, err)
+		}
+
+		t.Cleanup(func() {
+			if err := rec.Save(); err != nil {
+				t.Errorf("failed to save cassette %q: %v", path, err)
+			}
+		})
+
+		return rec
+	}
+}
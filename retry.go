@@ -0,0 +1,170 @@
+package kbhttp
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig configures Client.Do's retry behavior. A zero value (or
+// MaxAttempts <= 1) disables retries.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Retries are disabled when this is 0 or 1.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries, after Multiplier and
+	// jitter are applied.
+	MaxBackoff time.Duration
+	// Multiplier scales the backoff after each attempt. Defaults to 2
+	// when left at 0.
+	Multiplier float64
+
+	// RetryOn decides whether a given response/error pair should be
+	// retried. Defaults to retrying network errors and 502/503/504.
+	RetryOn func(*http.Response, error) bool
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotent marks a request made with ctx as safe to retry, even if
+// its method (typically POST) isn't normally considered idempotent.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete, http.MethodHead:
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	idempotent, _ := req.Context().Value(idempotencyKey{}).(bool)
+	return idempotent
+}
+
+func defaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// withRetry wraps next with RetryConfig's retry policy.
+func withRetry(config RetryConfig) func(http.RoundTripper) http.RoundTripper {
+	retryOn := config.RetryOn
+	if retryOn == nil {
+		retryOn = defaultRetryOn
+	}
+	multiplier := config.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !isIdempotent(req) {
+				return next.RoundTrip(req)
+			}
+
+			var resp *http.Response
+			var err error
+			backoff := config.InitialBackoff
+
+			for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+				resp, err = next.RoundTrip(req)
+				if attempt == config.MaxAttempts || !retryOn(resp, err) {
+					return resp, err
+				}
+
+				// Fetch the replacement body before touching resp, so
+				// that a request we can't replay leaves resp untouched
+				// and still readable by the caller.
+				var nextBody io.ReadCloser
+				if req.Body != nil {
+					if req.GetBody == nil {
+						return resp, err
+					}
+					var getErr error
+					nextBody, getErr = req.GetBody()
+					if getErr != nil {
+						return resp, err
+					}
+				}
+
+				wait := retryAfter(resp)
+				if wait == 0 {
+					wait = jitter(backoff)
+				}
+				if config.MaxBackoff > 0 && wait > config.MaxBackoff {
+					wait = config.MaxBackoff
+				}
+
+				if resp != nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				if nextBody != nil {
+					req.Body = nextBody
+				}
+
+				select {
+				case <-req.Context().Done():
+					return resp, req.Context().Err()
+				case <-time.After(wait):
+				}
+
+				backoff = time.Duration(float64(backoff) * multiplier)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// retryAfter parses a Retry-After response header (seconds or HTTP-date
+// form) and returns 0 if absent or unparseable.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// jitter returns a random duration in [d, 2d), to avoid retry storms from
+// clients backing off in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d + time.Duration(rand.Int63n(int64(math.Max(1, float64(d)))))
+}
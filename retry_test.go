@@ -0,0 +1,117 @@
+package kbhttp
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dankinder/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRetryOn503(t *testing.T) {
+	handler := httpmock.NewMockHandlerWithHeaders(t)
+	s := httpmock.NewServer(handler)
+	defer func() {
+		s.Close()
+		handler.AssertExpectations(t)
+	}()
+
+	client := NewClient(ClientConfig{
+		BaseURL: mustParse(s.URL()),
+		Retry: RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	})
+
+	handler.On("HandleWithHeaders", "GET", "/users/1", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Status: 503,
+	}).Twice()
+	handler.On("HandleWithHeaders", "GET", "/users/1", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Body: []byte(`{"name": "joebob"}`),
+	}).Once()
+
+	var u TestUser
+	require.NoError(t, client.GetJSON("/users/1", &u))
+	assert.Equal(t, "joebob", u.Name)
+}
+
+func TestClientRetryNotAppliedToPost(t *testing.T) {
+	handler := httpmock.NewMockHandlerWithHeaders(t)
+	s := httpmock.NewServer(handler)
+	defer func() {
+		s.Close()
+		handler.AssertExpectations(t)
+	}()
+
+	client := NewClient(ClientConfig{
+		BaseURL: mustParse(s.URL()),
+		Retry: RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	})
+
+	handler.On("HandleWithHeaders", "POST", "/users", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Status: 503,
+	}).Once()
+
+	u := &TestUser{Name: "joebob"}
+	err := client.PostJSON("/users", u, nil)
+	require.Error(t, err)
+	require.True(t, IsStatus(err, 503))
+}
+
+func TestClientRetryUnreplayableBodyReturnsReadableResponse(t *testing.T) {
+	handler := httpmock.NewMockHandlerWithHeaders(t)
+	s := httpmock.NewServer(handler)
+	defer func() {
+		s.Close()
+		handler.AssertExpectations(t)
+	}()
+
+	client := NewClient(ClientConfig{
+		BaseURL: mustParse(s.URL()),
+		Retry: RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	})
+
+	handler.On("HandleWithHeaders", "PUT", "/users/1", mock.Anything, mock.Anything).Return(httpmock.Response{
+		Status: 503,
+		Body:   []byte("important error detail"),
+	}).Once()
+
+	req, err := http.NewRequest(http.MethodPut, "/users/1", io.NopCloser(strings.NewReader("body")))
+	require.NoError(t, err)
+	require.Nil(t, req.GetBody)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, 503, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "important error detail", string(body))
+}
+
+func TestIsIdempotent(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, isIdempotent(get))
+
+	post, _ := http.NewRequest(http.MethodPost, "/", nil)
+	assert.False(t, isIdempotent(post))
+
+	post.Header.Set("Idempotency-Key", "abc")
+	assert.True(t, isIdempotent(post))
+
+	post2, _ := http.NewRequest(http.MethodPost, "/", nil)
+	post2 = post2.WithContext(WithIdempotent(post2.Context()))
+	assert.True(t, isIdempotent(post2))
+}